@@ -0,0 +1,173 @@
+package minhash
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+// ErrBBitMismatch is returned if a comparison is attempted between
+// BBitSignatures with different b or lane counts.
+var ErrBBitMismatch = errors.New("b-bit signature parameters do not match")
+
+// BBitSignature is a compressed encoding of a Minhash signature that keeps
+// only the low b bits of each minhash, bit-packed into a []uint64. For
+// b=1 this gives a signature ~64x smaller than the source Minhash, at the
+// cost of a small increase in similarity-estimate variance, making it a
+// good fit for on-disk indexes where space matters more than precision.
+type BBitSignature struct {
+	b   uint
+	k   int
+	sig []uint64
+}
+
+// BBitSignature reduces m's signature to its low b bits per lane, bit-packed
+// into a []uint64.
+func (m *Minhash) BBitSignature(b uint) *BBitSignature {
+	mask := uint64(1<<b) - 1
+
+	var sig []uint64
+	var w uint64
+	var filled uint // bits already used in w
+
+	for _, v := range m.sig {
+		val := v & mask
+
+		if filled+b <= 64 {
+			w |= val << filled
+			filled += b
+			continue
+		}
+
+		// val doesn't fit in the remaining space of w: write its low bits
+		// into what's left of w, push w, and start a fresh word with val's
+		// high bits.
+		lowBits := 64 - filled
+		highBits := b - lowBits
+
+		w |= (val & (uint64(1<<lowBits) - 1)) << filled
+		sig = append(sig, w)
+
+		w = val >> lowBits
+		filled = highBits
+	}
+
+	if filled != 0 {
+		sig = append(sig, w)
+	}
+
+	return &BBitSignature{b: b, k: len(m.sig), sig: sig}
+}
+
+// lane returns the i-th b-bit value packed into sig.
+func (s *BBitSignature) lane(i int) uint64 {
+	mask := uint64(1<<s.b) - 1
+
+	bitOffset := uint(i) * s.b
+	wordIdx := bitOffset / 64
+	filled := bitOffset % 64
+
+	if filled+s.b <= 64 {
+		return (s.sig[wordIdx] >> filled) & mask
+	}
+
+	lowBits := 64 - filled
+	highBits := s.b - lowBits
+
+	low := s.sig[wordIdx] >> filled
+	high := s.sig[wordIdx+1] & (uint64(1<<highBits) - 1)
+
+	return (low | (high << lowBits)) & mask
+}
+
+// BBitSimilarityOptions controls how BBitSignature.Similarity interprets the
+// fraction of matching lanes.
+type BBitSimilarityOptions struct {
+	// Corrected applies the Li-Konig unbiased correction for the bias b-bit
+	// minwise hashing introduces from collisions between distinct
+	// minhashes. Leave false to get the raw matching-lane fraction.
+	Corrected bool
+}
+
+// Similarity estimates the Jaccard similarity between two b-bit signatures
+// as the fraction of matching lanes C. With opts.Corrected, the Li-Konig
+// unbiased correction J = (C - 2^-b) / (1 - 2^-b) is applied and the result
+// is clamped to [0, 1].
+func (s *BBitSignature) Similarity(other *BBitSignature, opts BBitSimilarityOptions) (float64, error) {
+	if s.b != other.b || s.k != other.k {
+		return 0, ErrBBitMismatch
+	}
+
+	intersect := 0
+	for i := 0; i < s.k; i++ {
+		if s.lane(i) == other.lane(i) {
+			intersect++
+		}
+	}
+	c := float64(intersect) / float64(s.k)
+
+	if !opts.Corrected {
+		return c, nil
+	}
+
+	minOneProb := 1.0 / float64(uint64(1)<<s.b)
+	j := (c - minOneProb) / (1 - minOneProb)
+	if j < 0 {
+		return 0, nil
+	}
+	if j > 1 {
+		return 1, nil
+	}
+	return j, nil
+}
+
+// SimilarityBbitRaw computes the fraction of matching lanes between two
+// packed b-bit signatures produced by BBitSignature.MarshalBinary's word
+// layout, given the original lane count k. Unlike Similarity, it never
+// applies the Li-Konig correction, preserving the uncorrected behavior of
+// earlier b-bit comparisons.
+func SimilarityBbitRaw(sig1, sig2 []uint64, k int, b uint) (float64, error) {
+	if len(sig1) != len(sig2) {
+		return 0, ErrSigSizeMismatch
+	}
+	s1 := &BBitSignature{b: b, k: k, sig: sig1}
+	s2 := &BBitSignature{b: b, k: k, sig: sig2}
+	return s1.Similarity(s2, BBitSimilarityOptions{})
+}
+
+// MarshalBinary encodes s as: 1 byte b, 4 bytes lane count (little-endian
+// uint32), 4 bytes word count (little-endian uint32), then the packed
+// signature as little-endian uint64s.
+func (s *BBitSignature) MarshalBinary() ([]byte, error) {
+	buf := make([]byte, 1+4+4+8*len(s.sig))
+	buf[0] = byte(s.b)
+	binary.LittleEndian.PutUint32(buf[1:5], uint32(s.k))
+	binary.LittleEndian.PutUint32(buf[5:9], uint32(len(s.sig)))
+	for i, v := range s.sig {
+		binary.LittleEndian.PutUint64(buf[9+i*8:], v)
+	}
+	return buf, nil
+}
+
+// UnmarshalBinary decodes a signature produced by MarshalBinary.
+func (s *BBitSignature) UnmarshalBinary(data []byte) error {
+	if len(data) < 9 {
+		return errors.New("b-bit signature: truncated header")
+	}
+	b := uint(data[0])
+	k := int(binary.LittleEndian.Uint32(data[1:5]))
+	words := int(binary.LittleEndian.Uint32(data[5:9]))
+
+	if len(data) < 9+words*8 {
+		return errors.New("b-bit signature: truncated body")
+	}
+
+	sig := make([]uint64, words)
+	for i := range sig {
+		sig[i] = binary.LittleEndian.Uint64(data[9+i*8:])
+	}
+
+	s.b = b
+	s.k = k
+	s.sig = sig
+	return nil
+}
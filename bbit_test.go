@@ -0,0 +1,123 @@
+package minhash
+
+import (
+	"testing"
+
+	"github.com/zeebo/xxh3"
+)
+
+func TestBBitSignatureSimilarity(t *testing.T) {
+	var hashFunc = func(b []byte) (uint64, uint64) {
+		hasher := xxh3.Hash128(b)
+		return hasher.Lo, hasher.Hi
+	}
+
+	for _, k := range []int{8, 17, 64} {
+		for _, b := range []uint{1, 2, 4, 8, 16} {
+			m1 := NewMinhash(hashFunc, k)
+			m2 := NewMinhash(hashFunc, k)
+			m1.PushStrings(sentenceOne)
+			m2.PushStrings(sentenceTwo)
+
+			want, err := m1.Similarity(m2)
+			if err != nil {
+				t.Fatalf("k=%d b=%d: Similarity: %v", k, b, err)
+			}
+
+			s1 := m1.BBitSignature(b)
+			s2 := m2.BBitSignature(b)
+
+			got, err := s1.Similarity(s2, BBitSimilarityOptions{})
+			if err != nil {
+				t.Fatalf("k=%d b=%d: BBitSignature.Similarity: %v", k, b, err)
+			}
+
+			// b-bit similarity is a biased estimate of the full-signature
+			// similarity; it should still land in a sane range.
+			if got < 0 || got > 1 {
+				t.Fatalf("k=%d b=%d: got out-of-range similarity %v (full sig %v)", k, b, got, want)
+			}
+		}
+	}
+}
+
+func TestBBitSignatureCorrected(t *testing.T) {
+	var hashFunc = func(b []byte) (uint64, uint64) {
+		hasher := xxh3.Hash128(b)
+		return hasher.Lo, hasher.Hi
+	}
+
+	m1 := NewMinhash(hashFunc, 64)
+	m2 := NewMinhash(hashFunc, 64)
+	m1.PushStrings(sentenceOne)
+	m2.PushStrings(sentenceTwo)
+
+	s1 := m1.BBitSignature(1)
+	s2 := m2.BBitSignature(1)
+
+	raw, err := s1.Similarity(s2, BBitSimilarityOptions{})
+	if err != nil {
+		t.Fatalf("Similarity: %v", err)
+	}
+
+	corrected, err := s1.Similarity(s2, BBitSimilarityOptions{Corrected: true})
+	if err != nil {
+		t.Fatalf("Similarity (corrected): %v", err)
+	}
+
+	if corrected < 0 || corrected > 1 {
+		t.Fatalf("corrected similarity out of range: %v", corrected)
+	}
+	if raw == corrected {
+		t.Fatalf("expected correction to change the estimate, got %v for both", raw)
+	}
+}
+
+func TestBBitSignatureMismatch(t *testing.T) {
+	var hashFunc = func(b []byte) (uint64, uint64) {
+		hasher := xxh3.Hash128(b)
+		return hasher.Lo, hasher.Hi
+	}
+
+	m1 := NewMinhash(hashFunc, 8)
+	m2 := NewMinhash(hashFunc, 16)
+	m1.PushStrings(sentenceOne)
+	m2.PushStrings(sentenceTwo)
+
+	s1 := m1.BBitSignature(2)
+	s2 := m2.BBitSignature(2)
+
+	if _, err := s1.Similarity(s2, BBitSimilarityOptions{}); err != ErrBBitMismatch {
+		t.Fatalf("expected ErrBBitMismatch, got %v", err)
+	}
+}
+
+func TestBBitSignatureMarshalRoundTrip(t *testing.T) {
+	var hashFunc = func(b []byte) (uint64, uint64) {
+		hasher := xxh3.Hash128(b)
+		return hasher.Lo, hasher.Hi
+	}
+
+	m := NewMinhash(hashFunc, 17)
+	m.PushStrings(sentenceOne)
+
+	s := m.BBitSignature(4)
+
+	data, err := s.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary: %v", err)
+	}
+
+	var decoded BBitSignature
+	if err := decoded.UnmarshalBinary(data); err != nil {
+		t.Fatalf("UnmarshalBinary: %v", err)
+	}
+
+	sim, err := s.Similarity(&decoded, BBitSimilarityOptions{})
+	if err != nil {
+		t.Fatalf("Similarity: %v", err)
+	}
+	if sim != 1 {
+		t.Fatalf("expected round-tripped signature to be identical, got similarity %v", sim)
+	}
+}
@@ -0,0 +1,64 @@
+package minhash
+
+import "math"
+
+// Cardinality estimates the size of the set underlying m's signature using
+// the Cohen minimum-hash estimator, without needing the original multiset:
+// http://www.cohenwang.com/edith/Papers/tcest.pdf
+//
+// Signature slots that are still at their initial (empty) value are
+// excluded from the estimate; if every slot is empty, Cardinality returns 0.
+func (m *Minhash) Cardinality() uint64 {
+	sum := 0.0
+	k := 0
+	for _, v := range m.sig {
+		if v == ^uint64(0) {
+			continue
+		}
+		u := float64(math.MaxUint64-v) / float64(math.MaxUint64)
+		if u == 0 {
+			// The slot's normalized hash v/MaxUint64 is 1.0; -ln(0) is +Inf,
+			// so treat the slot as carrying no information rather than
+			// blowing up the estimate.
+			continue
+		}
+		sum += -math.Log(u)
+		k++
+	}
+	if k == 0 {
+		return 0
+	}
+	return uint64(float64(k-1) / sum)
+}
+
+// Union returns a new Minhash holding the signature of the union of m and
+// other's underlying sets, leaving both inputs untouched.
+func (m *Minhash) Union(other *Minhash) (*Minhash, error) {
+	if len(m.sig) != len(other.sig) {
+		return nil, ErrSigSizeMismatch
+	}
+	union := NewMinhashFromSigs(m.hashFunc, m.sig)
+	union.hashFuncID = m.hashFuncID
+	union.Merge(other)
+	return union, nil
+}
+
+// JaccardFromCardinalities derives the Jaccard similarity of a and b from
+// their cardinalities and the cardinality of their union, via the
+// inclusion-exclusion identity |A∩B| = |A| + |B| - |A∪B|.
+func JaccardFromCardinalities(a, b, union *Minhash) float64 {
+	ca := float64(a.Cardinality())
+	cb := float64(b.Cardinality())
+	cu := float64(union.Cardinality())
+
+	if cu == 0 {
+		return 0
+	}
+
+	intersection := ca + cb - cu
+	if intersection < 0 {
+		intersection = 0
+	}
+
+	return intersection / cu
+}
@@ -0,0 +1,94 @@
+package minhash
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/zeebo/xxh3"
+)
+
+const cardinalitySigSize = 256
+
+func TestCardinality(t *testing.T) {
+	hashFunc := func(b []byte) (uint64, uint64) {
+		hasher := xxh3.Hash128(b)
+		return hasher.Lo, hasher.Hi
+	}
+
+	sizes := []int{1e3, 1e5, 1e6}
+	if testing.Short() {
+		sizes = []int{1e3}
+	}
+
+	// The Cohen estimator's relative standard error is ~1/sqrt(k); allow a
+	// few standard deviations of slack so the test isn't flaky on a single
+	// random draw.
+	bound := 4 / math.Sqrt(float64(cardinalitySigSize))
+
+	for _, size := range sizes {
+		t.Run(fmt.Sprintf("n=%d", size), func(t *testing.T) {
+			m := NewMinhash(hashFunc, cardinalitySigSize)
+			r := rand.New(rand.NewSource(int64(size)))
+			for i := 0; i < size; i++ {
+				m.Push([]byte(fmt.Sprintf("%x-%d", r.Int63(), i)))
+			}
+
+			got := float64(m.Cardinality())
+			want := float64(size)
+
+			relErr := math.Abs(got-want) / want
+			if relErr > bound {
+				t.Fatalf("cardinality estimate %v too far from true size %v: relative error %v exceeds bound %v", got, want, relErr, bound)
+			}
+		})
+	}
+}
+
+func TestCardinalityEmpty(t *testing.T) {
+	hashFunc := func(b []byte) (uint64, uint64) {
+		hasher := xxh3.Hash128(b)
+		return hasher.Lo, hasher.Hi
+	}
+
+	m := NewMinhash(hashFunc, 8)
+	if got := m.Cardinality(); got != 0 {
+		t.Fatalf("expected 0 cardinality for empty Minhash, got %v", got)
+	}
+}
+
+func TestUnionAndJaccardFromCardinalities(t *testing.T) {
+	hashFunc := func(b []byte) (uint64, uint64) {
+		hasher := xxh3.Hash128(b)
+		return hasher.Lo, hasher.Hi
+	}
+
+	// A = {0..999}, B = {500..1499}: |A|=|B|=1000, |A∪B|=1500, |A∩B|=500,
+	// so the true Jaccard similarity is 500/1500.
+	m1 := NewMinhash(hashFunc, cardinalitySigSize)
+	m2 := NewMinhash(hashFunc, cardinalitySigSize)
+	for i := 0; i < 1000; i++ {
+		m1.Push([]byte(fmt.Sprintf("item-%d", i)))
+	}
+	for i := 500; i < 1500; i++ {
+		m2.Push([]byte(fmt.Sprintf("item-%d", i)))
+	}
+
+	union, err := m1.Union(m2)
+	if err != nil {
+		t.Fatalf("Union: %v", err)
+	}
+
+	want := 500.0 / 1500.0
+	got := JaccardFromCardinalities(m1, m2, union)
+	if math.Abs(got-want) > 0.15 {
+		t.Fatalf("JaccardFromCardinalities = %v, want close to %v", got, want)
+	}
+
+	// m1 must stay untouched by Union: its cardinality should still
+	// reflect its own 1000-element set, not the ~1500-element union.
+	if relErr := math.Abs(float64(m1.Cardinality())-1000) / 1000; relErr > 0.3 {
+		t.Fatalf("Union appears to have mutated m1: cardinality %v, want near 1000", m1.Cardinality())
+	}
+}
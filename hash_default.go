@@ -0,0 +1,42 @@
+package minhash
+
+import "hash/maphash"
+
+// defaultSeed1 and defaultSeed2 are fixed once per process so that
+// DefaultHashFunc produces stable signatures within a single run. They are
+// not reproducible across processes: persisting a Minhash built with
+// DefaultHashFunc and decoding it elsewhere will produce a working but
+// differently-seeded hash func. For signatures that must be reproducible
+// across processes (e.g. before calling MarshalBinary), use
+// SeededHashFunc with explicit seeds that are generated once, serialized
+// alongside the signature, and registered with RegisterHashFunc on decode.
+var defaultSeed1, defaultSeed2 = maphash.MakeSeed(), maphash.MakeSeed()
+
+// DefaultSeeds returns the seeds used by DefaultHashFunc.
+func DefaultSeeds() (maphash.Seed, maphash.Seed) {
+	return defaultSeed1, defaultSeed2
+}
+
+// SeededHashFunc returns a HashFunc built on the standard library's
+// hash/maphash, using seed1 and seed2 as its two independent hashes.
+// Passing the same seeds always produces the same HashFunc, making it
+// suitable for reproducible signatures across processes.
+func SeededHashFunc(seed1, seed2 maphash.Seed) HashFunc {
+	return func(b []byte) (uint64, uint64) {
+		return maphash.Bytes(seed1, b), maphash.Bytes(seed2, b)
+	}
+}
+
+// DefaultHashFunc returns a zero-dependency HashFunc built on
+// hash/maphash, seeded once at package init. It requires no third-party
+// hash library, but see defaultSeed1's documentation for its
+// reproducibility limits.
+func DefaultHashFunc() HashFunc {
+	return SeededHashFunc(defaultSeed1, defaultSeed2)
+}
+
+// NewMinhashDefault returns a new Minhash using DefaultHashFunc, with no
+// third-party dependency required.
+func NewMinhashDefault(size int) *Minhash {
+	return NewMinhash(DefaultHashFunc(), size)
+}
@@ -0,0 +1,93 @@
+package minhash
+
+import (
+	"hash/maphash"
+	"testing"
+)
+
+func TestDefaultHashFuncSimilarity(t *testing.T) {
+	// DefaultHashFunc is seeded randomly per process (see defaultSeed1's
+	// docs), so unlike the fixed xxh3/murmur3 adapters in minhash_test.go,
+	// its exact signature values aren't reproducible across runs. Assert
+	// only that it's usable and produces a similarity within range, and
+	// that it's stable within a single process.
+	m1 := NewMinhashDefault(8)
+	m2 := NewMinhashDefault(8)
+
+	m1.PushStrings(sentenceOne)
+	m2.PushStrings(sentenceTwo)
+
+	sim, err := m1.Similarity(m2)
+	if err != nil {
+		t.Fatalf("Similarity: %v", err)
+	}
+	if sim < 0 || sim > 1 {
+		t.Fatalf("Similarity out of range: %v", sim)
+	}
+
+	m3 := NewMinhashDefault(8)
+	m3.PushStrings(sentenceOne)
+	if sim2, err := m1.Similarity(m3); err != nil || sim2 != 1 {
+		t.Fatalf("expected two Minhashes from the same sentence to match exactly within a process, got sim=%v err=%v", sim2, err)
+	}
+}
+
+func TestSeededHashFuncIsStable(t *testing.T) {
+	seed1, seed2 := DefaultSeeds()
+	hashFunc := SeededHashFunc(seed1, seed2)
+
+	m1 := NewMinhash(hashFunc, len(sentenceOne))
+	m2 := NewMinhash(hashFunc, len(sentenceOne))
+
+	m1.PushStrings(sentenceOne)
+	m2.PushStrings(sentenceOne)
+
+	sim, err := m1.Similarity(m2)
+	if err != nil {
+		t.Fatalf("Similarity: %v", err)
+	}
+	if sim != 1 {
+		t.Fatalf("expected identical signatures from the same seeds, got similarity %v", sim)
+	}
+}
+
+func TestSeededHashFuncMatchesDefaultSeeds(t *testing.T) {
+	seed1, seed2 := DefaultSeeds()
+	hashFuncA := SeededHashFunc(seed1, seed2)
+	hashFuncB := DefaultHashFunc()
+
+	m1 := NewMinhash(hashFuncA, len(sentenceOne))
+	m2 := NewMinhash(hashFuncB, len(sentenceOne))
+
+	m1.PushStrings(sentenceOne)
+	m2.PushStrings(sentenceOne)
+
+	sim, err := m1.Similarity(m2)
+	if err != nil {
+		t.Fatalf("Similarity: %v", err)
+	}
+	// DefaultHashFunc uses DefaultSeeds internally, so rebuilding a
+	// HashFunc from those seeds should reproduce identical signatures.
+	if sim != 1 {
+		t.Fatalf("expected DefaultHashFunc to use DefaultSeeds, got similarity %v", sim)
+	}
+}
+
+func TestSeededHashFuncDiffersAcrossSeeds(t *testing.T) {
+	seed1, seed2 := maphash.MakeSeed(), maphash.MakeSeed()
+	otherSeed1, otherSeed2 := maphash.MakeSeed(), maphash.MakeSeed()
+
+	m1 := NewMinhash(SeededHashFunc(seed1, seed2), len(sentenceOne))
+	m2 := NewMinhash(SeededHashFunc(otherSeed1, otherSeed2), len(sentenceOne))
+
+	m1.PushStrings(sentenceOne)
+	m2.PushStrings(sentenceOne)
+
+	sim, err := m1.Similarity(m2)
+	if err != nil {
+		t.Fatalf("Similarity: %v", err)
+	}
+	if sim == 1 {
+		t.Fatalf("expected different seeds to (almost certainly) produce different signatures")
+	}
+}
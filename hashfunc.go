@@ -0,0 +1,69 @@
+package minhash
+
+import (
+	"errors"
+
+	"github.com/spaolacci/murmur3"
+	"github.com/zeebo/xxh3"
+)
+
+// ErrUnknownHashFunc is returned when decoding a signature whose
+// hash-family ID has no registered HashFunc.
+var ErrUnknownHashFunc = errors.New("minhash: unknown hash func id")
+
+// Registered hash-family IDs for the built-in HashFuncs.
+const (
+	HashFuncXXH3128    uint8 = 1
+	HashFuncMurmur3128 uint8 = 2
+)
+
+type registeredHashFunc struct {
+	name string
+	fn   HashFunc
+}
+
+var hashFuncRegistry = make(map[uint8]registeredHashFunc)
+
+// RegisterHashFunc registers fn under id so that signatures marshaled with
+// that id can be decoded back into a usable Minhash. id 0 is reserved for
+// unregistered hash funcs and cannot be registered.
+func RegisterHashFunc(id uint8, name string, fn HashFunc) {
+	if id == 0 {
+		panic("minhash: hash func id 0 is reserved")
+	}
+	hashFuncRegistry[id] = registeredHashFunc{name: name, fn: fn}
+}
+
+// lookupHashFunc returns the HashFunc registered under id, if any.
+func lookupHashFunc(id uint8) (HashFunc, bool) {
+	r, ok := hashFuncRegistry[id]
+	if !ok {
+		return nil, false
+	}
+	return r.fn, true
+}
+
+func init() {
+	RegisterHashFunc(HashFuncXXH3128, "xxh3-128", func(b []byte) (uint64, uint64) {
+		h := xxh3.Hash128(b)
+		return h.Lo, h.Hi
+	})
+	RegisterHashFunc(HashFuncMurmur3128, "murmur3-128", func(b []byte) (uint64, uint64) {
+		h := murmur3.New128()
+		h.Write(b)
+		hi, lo := h.Sum128()
+		return hi, lo
+	})
+}
+
+// NewMinhashWithID returns a new Minhash using the HashFunc registered under
+// id, recording id so the signature can later be marshaled and unmarshaled.
+func NewMinhashWithID(id uint8, size int) (*Minhash, error) {
+	fn, ok := lookupHashFunc(id)
+	if !ok {
+		return nil, ErrUnknownHashFunc
+	}
+	m := NewMinhash(fn, size)
+	m.hashFuncID = id
+	return m, nil
+}
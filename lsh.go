@@ -0,0 +1,235 @@
+package minhash
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+	"math"
+)
+
+// LSH implements locality-sensitive hashing over Minhash signatures using the
+// standard banding technique: a signature is split into contiguous bands,
+// each band is hashed to a bucket key, and two signatures that land in the
+// same bucket for any band are considered candidates for being similar.
+type LSH struct {
+	bands int
+	rows  int
+
+	tables []map[uint64][]string
+	items  map[string]*Minhash
+}
+
+// NewLSH returns a new LSH index that splits signatures of length
+// bands*rows into the given number of bands, each of the given number of
+// rows.
+func NewLSH(bands, rows int) *LSH {
+	tables := make([]map[uint64][]string, bands)
+	for i := range tables {
+		tables[i] = make(map[uint64][]string)
+	}
+	return &LSH{
+		bands:  bands,
+		rows:   rows,
+		tables: tables,
+		items:  make(map[string]*Minhash),
+	}
+}
+
+// Insert adds a signature to the index under the given id.
+func (l *LSH) Insert(id string, m *Minhash) error {
+	keys, err := l.bucketKeys(m)
+	if err != nil {
+		return err
+	}
+	for band, key := range keys {
+		l.tables[band][key] = append(l.tables[band][key], id)
+	}
+	l.items[id] = m
+	return nil
+}
+
+// InsertAll inserts every signature in items, keyed by id.
+func (l *LSH) InsertAll(items map[string]*Minhash) error {
+	for id, m := range items {
+		if err := l.Insert(id, m); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Remove deletes id from the index.
+func (l *LSH) Remove(id string) {
+	m, ok := l.items[id]
+	if !ok {
+		l.removeByScan(id)
+		return
+	}
+
+	// The signature is still available, so we can recompute exactly which
+	// buckets it landed in rather than scanning every bucket of every band.
+	keys, err := l.bucketKeys(m)
+	if err != nil {
+		l.removeByScan(id)
+		return
+	}
+	for band, key := range keys {
+		l.removeFromBucket(band, key, id)
+	}
+	delete(l.items, id)
+}
+
+// removeByScan removes id from every bucket of every band table. It's the
+// fallback used when id's signature isn't available to recompute its
+// buckets directly.
+func (l *LSH) removeByScan(id string) {
+	for band, table := range l.tables {
+		for key := range table {
+			l.removeFromBucket(band, key, id)
+		}
+	}
+	delete(l.items, id)
+}
+
+// removeFromBucket deletes id from the bucket at tables[band][key].
+func (l *LSH) removeFromBucket(band int, key uint64, id string) {
+	ids := l.tables[band][key]
+	filtered := ids[:0]
+	for _, existing := range ids {
+		if existing != id {
+			filtered = append(filtered, existing)
+		}
+	}
+	if len(filtered) == 0 {
+		delete(l.tables[band], key)
+	} else {
+		l.tables[band][key] = filtered
+	}
+}
+
+// Query returns the deduplicated union of candidate ids that share at least
+// one band bucket with m. Candidates are approximate: callers that need a
+// similarity guarantee should verify with Minhash.Similarity, or use
+// QueryThreshold.
+func (l *LSH) Query(m *Minhash) ([]string, error) {
+	keys, err := l.bucketKeys(m)
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[string]struct{})
+	var results []string
+	for band, key := range keys {
+		for _, id := range l.tables[band][key] {
+			if _, ok := seen[id]; ok {
+				continue
+			}
+			seen[id] = struct{}{}
+			results = append(results, id)
+		}
+	}
+	return results, nil
+}
+
+// QueryThreshold returns the ids of indexed signatures whose estimated
+// Jaccard similarity with m is at least jaccard. It first narrows the search
+// to LSH candidates via Query, then verifies each candidate's similarity
+// against its stored signature.
+func (l *LSH) QueryThreshold(m *Minhash, jaccard float64) ([]string, error) {
+	candidates, err := l.Query(m)
+	if err != nil {
+		return nil, err
+	}
+	var results []string
+	for _, id := range candidates {
+		other, ok := l.items[id]
+		if !ok {
+			continue
+		}
+		sim, err := m.Similarity(other)
+		if err != nil {
+			return nil, err
+		}
+		if sim >= jaccard {
+			results = append(results, id)
+		}
+	}
+	return results, nil
+}
+
+// bucketKeys hashes each band of m's signature to a bucket key, one per
+// band.
+func (l *LSH) bucketKeys(m *Minhash) ([]uint64, error) {
+	sig := m.Signature()
+	if len(sig) != l.bands*l.rows {
+		return nil, ErrSigSizeMismatch
+	}
+	buf := make([]byte, 8*l.rows)
+	keys := make([]uint64, l.bands)
+	for band := 0; band < l.bands; band++ {
+		start := band * l.rows
+		for i := 0; i < l.rows; i++ {
+			binary.LittleEndian.PutUint64(buf[i*8:], sig[start+i])
+		}
+		h := fnv.New64a()
+		h.Write(buf)
+		keys[band] = h.Sum64()
+	}
+	return keys, nil
+}
+
+// OptimalParams picks the (bands, rows) pair that divides a signature of
+// length sigSize and minimizes the combined false-positive and
+// false-negative area around the given Jaccard threshold, using the
+// standard LSH S-curve 1 - (1 - s^rows)^bands.
+func OptimalParams(sigSize int, threshold float64) (bands, rows int) {
+	bestBands, bestRows := 1, sigSize
+	bestError := math.MaxFloat64
+
+	for r := 1; r <= sigSize; r++ {
+		if sigSize%r != 0 {
+			continue
+		}
+		b := sigSize / r
+
+		err := falsePositiveArea(threshold, b, r) + falseNegativeArea(threshold, b, r)
+		if err < bestError {
+			bestError = err
+			bestBands, bestRows = b, r
+		}
+	}
+
+	return bestBands, bestRows
+}
+
+// lshCurveSteps is the number of samples used to numerically integrate the
+// LSH S-curve when grid-searching for optimal parameters.
+const lshCurveSteps = 200
+
+// falsePositiveArea integrates the probability of a candidate pair below
+// the similarity threshold being reported as a match.
+func falsePositiveArea(threshold float64, bands, rows int) float64 {
+	area := 0.0
+	step := threshold / lshCurveSteps
+	for i := 0; i < lshCurveSteps; i++ {
+		s := (float64(i) + 0.5) * step
+		area += sCurve(s, bands, rows) * step
+	}
+	return area
+}
+
+// falseNegativeArea integrates the probability of a candidate pair at or
+// above the similarity threshold being missed.
+func falseNegativeArea(threshold float64, bands, rows int) float64 {
+	area := 0.0
+	step := (1.0 - threshold) / lshCurveSteps
+	for i := 0; i < lshCurveSteps; i++ {
+		s := threshold + (float64(i)+0.5)*step
+		area += (1.0 - sCurve(s, bands, rows)) * step
+	}
+	return area
+}
+
+// sCurve is the probability that two signatures with true similarity s
+// share at least one band bucket.
+func sCurve(s float64, bands, rows int) float64 {
+	return 1.0 - math.Pow(1.0-math.Pow(s, float64(rows)), float64(bands))
+}
@@ -0,0 +1,133 @@
+package minhash
+
+import (
+	"testing"
+
+	"github.com/zeebo/xxh3"
+)
+
+func lshHashFunc() HashFunc {
+	return func(b []byte) (uint64, uint64) {
+		hasher := xxh3.Hash128(b)
+		return hasher.Lo, hasher.Hi
+	}
+}
+
+func lshSigSize() int { return 16 }
+
+func newLSHMinhash(hashFunc HashFunc, words []string) *Minhash {
+	m := NewMinhash(hashFunc, lshSigSize())
+	m.PushStrings(words)
+	return m
+}
+
+func TestLSHQueryFindsNearDuplicate(t *testing.T) {
+	hashFunc := lshHashFunc()
+
+	near := newLSHMinhash(hashFunc, sentenceOne)
+	dup := newLSHMinhash(hashFunc, sentenceTwo) // near-duplicate of sentenceOne
+	far := newLSHMinhash(hashFunc, []string{"completely", "unrelated", "words", "here"})
+
+	l := NewLSH(16, 1)
+	if err := l.InsertAll(map[string]*Minhash{
+		"near": near,
+		"far":  far,
+	}); err != nil {
+		t.Fatalf("InsertAll: %v", err)
+	}
+
+	results, err := l.Query(dup)
+	if err != nil {
+		t.Fatalf("Query: %v", err)
+	}
+
+	var foundNear, foundFar bool
+	for _, id := range results {
+		switch id {
+		case "near":
+			foundNear = true
+		case "far":
+			foundFar = true
+		}
+	}
+	if !foundNear {
+		t.Fatalf("expected near-duplicate %q to be a candidate, got %v", "near", results)
+	}
+	if foundFar {
+		t.Fatalf("expected dissimilar item %q not to be a candidate, got %v", "far", results)
+	}
+}
+
+func TestLSHQueryThreshold(t *testing.T) {
+	hashFunc := lshHashFunc()
+
+	dup := newLSHMinhash(hashFunc, sentenceTwo)
+
+	l := NewLSH(16, 1)
+	if err := l.Insert("near", newLSHMinhash(hashFunc, sentenceOne)); err != nil {
+		t.Fatalf("Insert: %v", err)
+	}
+	if err := l.Insert("far", newLSHMinhash(hashFunc, []string{"completely", "unrelated", "words", "here"})); err != nil {
+		t.Fatalf("Insert: %v", err)
+	}
+
+	results, err := l.QueryThreshold(dup, 0.3)
+	if err != nil {
+		t.Fatalf("QueryThreshold: %v", err)
+	}
+	if len(results) != 1 || results[0] != "near" {
+		t.Fatalf("expected only %q above threshold, got %v", "near", results)
+	}
+}
+
+func TestLSHQuerySigSizeMismatch(t *testing.T) {
+	hashFunc := lshHashFunc()
+
+	l := NewLSH(16, 1)
+	wrongSize := NewMinhash(hashFunc, lshSigSize()+1)
+
+	if _, err := l.Query(wrongSize); err != ErrSigSizeMismatch {
+		t.Fatalf("expected ErrSigSizeMismatch, got %v", err)
+	}
+}
+
+func TestLSHRemove(t *testing.T) {
+	hashFunc := lshHashFunc()
+
+	near := newLSHMinhash(hashFunc, sentenceOne)
+	dup := newLSHMinhash(hashFunc, sentenceTwo)
+
+	l := NewLSH(16, 1)
+	if err := l.Insert("near", near); err != nil {
+		t.Fatalf("Insert: %v", err)
+	}
+
+	l.Remove("near")
+
+	results, err := l.Query(dup)
+	if err != nil {
+		t.Fatalf("Query: %v", err)
+	}
+	for _, id := range results {
+		if id == "near" {
+			t.Fatalf("expected %q to be removed from results, got %v", "near", results)
+		}
+	}
+}
+
+func TestOptimalParamsMonotonic(t *testing.T) {
+	const sigSize = 64
+
+	_, lowRows := OptimalParams(sigSize, 0.2)
+	_, highRows := OptimalParams(sigSize, 0.8)
+
+	if sigSize%lowRows != 0 || sigSize%highRows != 0 {
+		t.Fatalf("expected rows to divide sigSize, got low=%d high=%d for size %d", lowRows, highRows, sigSize)
+	}
+
+	// A higher similarity threshold should favor more rows per band (a
+	// steeper S-curve), to avoid false positives among dissimilar pairs.
+	if lowRows > highRows {
+		t.Fatalf("expected rows to increase with threshold, got %d rows at 0.2 and %d rows at 0.8", lowRows, highRows)
+	}
+}
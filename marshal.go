@@ -0,0 +1,200 @@
+package minhash
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"io"
+)
+
+// minhashMagic identifies the binary wire format used by MarshalBinary.
+var minhashMagic = [4]byte{'M', 'H', '0', '1'}
+
+// minhashVersion is the current binary wire format version.
+const minhashVersion = 1
+
+// minhashHeaderSize is the size, in bytes, of the fixed binary header:
+// magic (4) + version (1) + hash func id (1) + signature length (4).
+const minhashHeaderSize = 4 + 1 + 1 + 4
+
+// MarshalBinary encodes m into the wire format: a 4-byte magic "MH01", a
+// 1-byte version, a 1-byte hash-family ID (see RegisterHashFunc), a 4-byte
+// little-endian signature length, then the signature as little-endian
+// uint64s. The hash func must have been obtained from the registry (via
+// NewMinhashWithID or a prior UnmarshalBinary) or ErrUnknownHashFunc is
+// returned, since the hash func couldn't otherwise be restored on decode.
+func (m *Minhash) MarshalBinary() ([]byte, error) {
+	if _, ok := lookupHashFunc(m.hashFuncID); !ok {
+		return nil, ErrUnknownHashFunc
+	}
+
+	buf := make([]byte, minhashHeaderSize+8*len(m.sig))
+	copy(buf[0:4], minhashMagic[:])
+	buf[4] = minhashVersion
+	buf[5] = m.hashFuncID
+	binary.LittleEndian.PutUint32(buf[6:10], uint32(len(m.sig)))
+	for i, v := range m.sig {
+		binary.LittleEndian.PutUint64(buf[minhashHeaderSize+i*8:], v)
+	}
+	return buf, nil
+}
+
+// UnmarshalBinary decodes a signature produced by MarshalBinary, looking up
+// its hash func in the registry so the result remains usable via Push and
+// Merge. It returns ErrUnknownHashFunc if the encoded hash-family ID isn't
+// registered.
+func (m *Minhash) UnmarshalBinary(data []byte) error {
+	sig, hashFuncID, err := decodeMinhashBinary(data)
+	if err != nil {
+		return err
+	}
+	fn, ok := lookupHashFunc(hashFuncID)
+	if !ok {
+		return ErrUnknownHashFunc
+	}
+	m.sig = sig
+	m.hashFunc = fn
+	m.hashFuncID = hashFuncID
+	return nil
+}
+
+func decodeMinhashBinary(data []byte) (sig []uint64, hashFuncID uint8, err error) {
+	if len(data) < minhashHeaderSize {
+		return nil, 0, errors.New("minhash: truncated header")
+	}
+	if [4]byte(data[0:4]) != minhashMagic {
+		return nil, 0, errors.New("minhash: bad magic")
+	}
+	if data[4] != minhashVersion {
+		return nil, 0, errors.New("minhash: unsupported version")
+	}
+	hashFuncID = data[5]
+	sigLen := int(binary.LittleEndian.Uint32(data[6:10]))
+
+	if len(data) < minhashHeaderSize+sigLen*8 {
+		return nil, 0, errors.New("minhash: truncated signature")
+	}
+
+	sig = make([]uint64, sigLen)
+	for i := range sig {
+		sig[i] = binary.LittleEndian.Uint64(data[minhashHeaderSize+i*8:])
+	}
+	return sig, hashFuncID, nil
+}
+
+// jsonMinhash is the JSON wire representation of a Minhash.
+type jsonMinhash struct {
+	Version    uint8    `json:"version"`
+	HashFuncID uint8    `json:"hashFuncId"`
+	Signature  []uint64 `json:"signature"`
+}
+
+// MarshalJSON encodes m using the same version and hash-family ID as
+// MarshalBinary, with the signature written as a JSON array.
+func (m *Minhash) MarshalJSON() ([]byte, error) {
+	if _, ok := lookupHashFunc(m.hashFuncID); !ok {
+		return nil, ErrUnknownHashFunc
+	}
+	return json.Marshal(jsonMinhash{
+		Version:    minhashVersion,
+		HashFuncID: m.hashFuncID,
+		Signature:  m.sig,
+	})
+}
+
+// UnmarshalJSON decodes a signature produced by MarshalJSON, looking up its
+// hash func in the registry so the result remains usable via Push and
+// Merge.
+func (m *Minhash) UnmarshalJSON(data []byte) error {
+	var j jsonMinhash
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+	if j.Version != minhashVersion {
+		return errors.New("minhash: unsupported version")
+	}
+	fn, ok := lookupHashFunc(j.HashFuncID)
+	if !ok {
+		return ErrUnknownHashFunc
+	}
+	m.sig = j.Signature
+	m.hashFunc = fn
+	m.hashFuncID = j.HashFuncID
+	return nil
+}
+
+// NewMinhashFromReader reads a single signature written by WriteTo (or
+// MarshalBinary) from r.
+func NewMinhashFromReader(r io.Reader) (*Minhash, error) {
+	header := make([]byte, minhashHeaderSize)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, err
+	}
+	sigLen := int(binary.LittleEndian.Uint32(header[6:10]))
+
+	body := make([]byte, 8*sigLen)
+	if _, err := io.ReadFull(r, body); err != nil {
+		return nil, err
+	}
+
+	m := &Minhash{}
+	if err := m.UnmarshalBinary(append(header, body...)); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// WriteTo writes m in the MarshalBinary wire format to w, implementing
+// io.WriterTo.
+func (m *Minhash) WriteTo(w io.Writer) (int64, error) {
+	data, err := m.MarshalBinary()
+	if err != nil {
+		return 0, err
+	}
+	n, err := w.Write(data)
+	return int64(n), err
+}
+
+// WriteSignatures writes each of sigs to w back-to-back, length-prefixed so
+// they can be read back with ReadSignatures without wrapping them in gob.
+func WriteSignatures(w io.Writer, sigs []*Minhash) error {
+	for _, m := range sigs {
+		data, err := m.MarshalBinary()
+		if err != nil {
+			return err
+		}
+		if err := binary.Write(w, binary.LittleEndian, uint32(len(data))); err != nil {
+			return err
+		}
+		if _, err := w.Write(data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReadSignatures reads signatures written by WriteSignatures from r until
+// EOF.
+func ReadSignatures(r io.Reader) ([]*Minhash, error) {
+	var sigs []*Minhash
+	for {
+		var n uint32
+		if err := binary.Read(r, binary.LittleEndian, &n); err != nil {
+			if err == io.EOF {
+				return sigs, nil
+			}
+			return nil, err
+		}
+
+		data := make([]byte, n)
+		if _, err := io.ReadFull(r, data); err != nil {
+			return nil, err
+		}
+
+		m := &Minhash{}
+		if err := m.UnmarshalBinary(data); err != nil {
+			return nil, err
+		}
+		sigs = append(sigs, m)
+	}
+}
@@ -0,0 +1,138 @@
+package minhash
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestMinhashBinaryRoundTrip(t *testing.T) {
+	m, err := NewMinhashWithID(HashFuncXXH3128, len(sentenceOne))
+	if err != nil {
+		t.Fatalf("NewMinhashWithID: %v", err)
+	}
+	m.PushStrings(sentenceOne)
+
+	data, err := m.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary: %v", err)
+	}
+
+	var decoded Minhash
+	if err := decoded.UnmarshalBinary(data); err != nil {
+		t.Fatalf("UnmarshalBinary: %v", err)
+	}
+
+	sim, err := m.Similarity(&decoded)
+	if err != nil {
+		t.Fatalf("Similarity: %v", err)
+	}
+	if sim != 1 {
+		t.Fatalf("expected round-tripped signature to be identical, got similarity %v", sim)
+	}
+
+	// The decoded signature must still be usable.
+	decoded.Push([]byte("another"))
+}
+
+func TestMinhashBinaryUnregisteredHashFunc(t *testing.T) {
+	m := NewMinhash(func(b []byte) (uint64, uint64) { return 0, 0 }, 4)
+	if _, err := m.MarshalBinary(); err != ErrUnknownHashFunc {
+		t.Fatalf("expected ErrUnknownHashFunc, got %v", err)
+	}
+}
+
+func TestMinhashBinaryUnknownHashFuncID(t *testing.T) {
+	m, err := NewMinhashWithID(HashFuncXXH3128, 4)
+	if err != nil {
+		t.Fatalf("NewMinhashWithID: %v", err)
+	}
+	data, err := m.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary: %v", err)
+	}
+	data[5] = 255 // no hash func registered under this id
+
+	var decoded Minhash
+	if err := decoded.UnmarshalBinary(data); err != ErrUnknownHashFunc {
+		t.Fatalf("expected ErrUnknownHashFunc, got %v", err)
+	}
+}
+
+func TestMinhashJSONRoundTrip(t *testing.T) {
+	m, err := NewMinhashWithID(HashFuncMurmur3128, len(sentenceOne))
+	if err != nil {
+		t.Fatalf("NewMinhashWithID: %v", err)
+	}
+	m.PushStrings(sentenceOne)
+
+	data, err := m.MarshalJSON()
+	if err != nil {
+		t.Fatalf("MarshalJSON: %v", err)
+	}
+
+	var decoded Minhash
+	if err := decoded.UnmarshalJSON(data); err != nil {
+		t.Fatalf("UnmarshalJSON: %v", err)
+	}
+
+	sim, err := m.Similarity(&decoded)
+	if err != nil {
+		t.Fatalf("Similarity: %v", err)
+	}
+	if sim != 1 {
+		t.Fatalf("expected round-tripped signature to be identical, got similarity %v", sim)
+	}
+}
+
+func TestMinhashReaderWriter(t *testing.T) {
+	m, err := NewMinhashWithID(HashFuncXXH3128, len(sentenceOne))
+	if err != nil {
+		t.Fatalf("NewMinhashWithID: %v", err)
+	}
+	m.PushStrings(sentenceOne)
+
+	var buf bytes.Buffer
+	if _, err := m.WriteTo(&buf); err != nil {
+		t.Fatalf("WriteTo: %v", err)
+	}
+
+	decoded, err := NewMinhashFromReader(&buf)
+	if err != nil {
+		t.Fatalf("NewMinhashFromReader: %v", err)
+	}
+
+	sim, err := m.Similarity(decoded)
+	if err != nil {
+		t.Fatalf("Similarity: %v", err)
+	}
+	if sim != 1 {
+		t.Fatalf("expected round-tripped signature to be identical, got similarity %v", sim)
+	}
+}
+
+func TestReadWriteSignatures(t *testing.T) {
+	m1, _ := NewMinhashWithID(HashFuncXXH3128, len(sentenceOne))
+	m1.PushStrings(sentenceOne)
+	m2, _ := NewMinhashWithID(HashFuncMurmur3128, len(sentenceTwo))
+	m2.PushStrings(sentenceTwo)
+
+	var buf bytes.Buffer
+	if err := WriteSignatures(&buf, []*Minhash{m1, m2}); err != nil {
+		t.Fatalf("WriteSignatures: %v", err)
+	}
+
+	decoded, err := ReadSignatures(&buf)
+	if err != nil {
+		t.Fatalf("ReadSignatures: %v", err)
+	}
+	if len(decoded) != 2 {
+		t.Fatalf("expected 2 signatures, got %d", len(decoded))
+	}
+
+	if sim, err := m1.Similarity(decoded[0]); err != nil || sim != 1 {
+		t.Fatalf("first signature mismatch: sim=%v err=%v", sim, err)
+	}
+	if sim, err := m2.Similarity(decoded[1]); err != nil || sim != 1 {
+		t.Fatalf("second signature mismatch: sim=%v err=%v", sim, err)
+	}
+}
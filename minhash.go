@@ -11,8 +11,9 @@ var ErrSigSizeMismatch = errors.New("signature sizes do not match")
 // collection of minimum hashes for the supplied data. The hash function is
 // used to populate the signature.
 type Minhash struct {
-	sig      []uint64
-	hashFunc HashFunc
+	sig        []uint64
+	hashFunc   HashFunc
+	hashFuncID uint8 // 0 if hashFunc wasn't obtained from the registry
 }
 
 // HashFunc describes any func that takes []byte and returns uin64.
@@ -104,80 +105,3 @@ func (m *Minhash) Similarity(other *Minhash) (float64, error) {
 	}
 	return float64(intersect) / float64(len(m.sig)), nil
 }
-
-// =========================================================================
-
-// // Cardinality estimates the cardinality of the set
-// func (m *Minhash) Cardinality() int {
-// 	// http://www.cohenwang.com/edith/Papers/tcest.pdf
-// 	sum := 0.0
-// 	for _, v := range m.sig {
-// 		sum += -math.Log(float64(math.MaxUint64-v) / float64(math.MaxUint64))
-// 	}
-// 	return int(float64(len(m.sig)-1) / sum)
-// }
-
-// // SignatureBbit returns a b-bit reduction of the signature. This will result
-// // in unused bits at the high-end of the words if b does not divide 64
-// // evenly.
-// func (m *Minhash) SignatureBbit(b uint) []uint64 {
-// 	var sig []uint64 // full signature
-// 	var w uint64     // current word
-// 	bits := uint(64) // bits free in current word
-//
-// 	mask := uint64(1<<b) - 1
-//
-// 	for _, v := range m.sig {
-// 		if bits >= b {
-// 			w <<= b
-// 			w |= v & mask
-// 			bits -= b
-// 		} else {
-// 			sig = append(sig, w)
-// 			w = 0
-// 			bits = 64
-// 		}
-// 	}
-//
-// 	if bits != 64 {
-// 		sig = append(sig, w)
-// 	}
-//
-// 	return sig
-// }
-
-// // SimilarityBbit computes an estimate for the similarity between two b-bit
-// // signatures
-// func SimilarityBbit(sig1, sig2 []uint64, b uint) (float64, error) {
-// 	if len(sig1) != len(sig2) {
-// 		return float64(0), ErrSigSizeMismatch
-// 	}
-//
-// 	intersect := 0
-// 	count := 0
-//
-// 	mask := uint64(1<<b) - 1
-//
-// 	for i := range sig1 {
-// 		w1 := sig1[i]
-// 		w2 := sig2[i]
-//
-// 		bits := uint(64)
-//
-// 		for bits >= b {
-// 			v1 := (w1 & mask)
-// 			v2 := (w2 & mask)
-//
-// 			count++
-// 			if v1 == v2 {
-// 				intersect++
-// 			}
-//
-// 			bits -= b
-// 			w1 >>= b
-// 			w2 >>= b
-// 		}
-// 	}
-//
-// 	return float64(intersect) / float64(count), nil
-// }